@@ -1,6 +1,7 @@
 package operator
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -9,7 +10,7 @@ import (
 	osconfigv1 "github.com/openshift/api/config/v1"
 	osconfig "github.com/openshift/client-go/config/clientset/versioned"
 	"github.com/openshift/cluster-autoscaler-operator/pkg/version"
-	cvorm "github.com/openshift/cluster-version-operator/lib/resourcemerge"
+	v1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,20 +25,30 @@ const (
 	ReasonEmpty             = ""
 	ReasonMissingDependency = "MissingDependency"
 	ReasonSyncing           = "SyncingResources"
+	ReasonUpgradeable       = "Upgradeable"
+	ReasonNotUpgradeable    = "PreconditionNotMet"
+	ReasonDegradedPending   = "DegradingFailure"
 )
 
 // StatusReporter reports the status of the operator to the OpenShift
 // cluster-version-operator via ClusterOperator resource status.
 type StatusReporter struct {
-	client         osconfig.Interface
-	relatedObjects []configv1.ObjectReference
+	client                osconfig.Interface
+	relatedObjects        []configv1.ObjectReference
+	preconditions         []Precondition
+	dependencies          []Dependency
+	operandVersionSources []OperandVersionSource
+	degradedTracker       *failureTracker
+	degradedWindow        time.Duration
 }
 
 // NewStatusReporter returns a new StatusReporter instance.
 func NewStatusReporter(cfg *rest.Config, relatedObjects []configv1.ObjectReference) (*StatusReporter, error) {
 	var err error
 	reporter := &StatusReporter{
-		relatedObjects: relatedObjects,
+		relatedObjects:  relatedObjects,
+		degradedTracker: newFailureTracker(nil),
+		degradedWindow:  DefaultDegradedWindow,
 	}
 
 	// Create a client for OpenShift config objects.
@@ -46,9 +57,100 @@ func NewStatusReporter(cfg *rest.Config, relatedObjects []configv1.ObjectReferen
 		return nil, err
 	}
 
+	reporter.AddPrecondition(NewLegacyFieldPrecondition(cfg))
+	reporter.AddPrecondition(NewMachineSetExistsPrecondition(cfg))
+	reporter.AddPrecondition(NewOperandImagePrecondition(cfg))
+
+	reporter.AddDependency(NewClusterOperatorDependency(reporter.client, "machine-api"))
+
+	reporter.AddOperandVersionSource(NewDeploymentOperandVersionSource(
+		cfg, "cluster-autoscaler", OperatorNamespace, ClusterAutoscalerName,
+	))
+
 	return reporter, nil
 }
 
+// AddPrecondition registers a Precondition that will be checked on every
+// report cycle.  If a registered precondition fails, the operator is
+// reported as not Upgradeable until the precondition passes again.
+func (r *StatusReporter) AddPrecondition(p Precondition) {
+	r.preconditions = append(r.preconditions, p)
+}
+
+// CheckPreconditions runs all registered preconditions and returns a
+// ClusterOperatorStatusCondition of type Upgradeable summarizing the
+// result.  If one or more preconditions fail, Upgradeable is set to
+// false with a reason and message naming the checks that failed.
+func (r *StatusReporter) CheckPreconditions() configv1.ClusterOperatorStatusCondition {
+	var failures []string
+
+	for _, p := range r.preconditions {
+		if err := p.Check(context.TODO()); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", p.Name(), err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return configv1.ClusterOperatorStatusCondition{
+			Type:    configv1.OperatorUpgradeable,
+			Status:  configv1.ConditionFalse,
+			Reason:  ReasonNotUpgradeable,
+			Message: strings.Join(failures, "; "),
+		}
+	}
+
+	return configv1.ClusterOperatorStatusCondition{
+		Type:   configv1.OperatorUpgradeable,
+		Status: configv1.ConditionTrue,
+		Reason: ReasonUpgradeable,
+	}
+}
+
+// AddOperandVersionSource registers an OperandVersionSource whose
+// result will be included in the ClusterOperator's reported Versions
+// on every report cycle.
+func (r *StatusReporter) AddOperandVersionSource(s OperandVersionSource) {
+	r.operandVersionSources = append(r.operandVersionSources, s)
+}
+
+// operandVersions returns the Versions to report on the
+// ClusterOperator: the operator's own binary version, plus one entry
+// per registered OperandVersionSource that resolves without error; a
+// source that fails is left out and counts as not rolled out. It also
+// reports whether every source has finished rolling out to the version
+// it returned, which drives Progressing independently of
+// version-string comparisons.
+func (r *StatusReporter) operandVersions() ([]configv1.OperandVersion, bool) {
+	versions := []configv1.OperandVersion{
+		{
+			Name:    "operator",
+			Version: version.Raw,
+		},
+	}
+
+	rolledOut := true
+
+	for _, s := range r.operandVersionSources {
+		v, sourceRolledOut, err := s.Version(context.TODO())
+		if err != nil {
+			glog.Errorf("failed to resolve %s operand version: %v", s.Name(), err)
+			rolledOut = false
+			continue
+		}
+
+		versions = append(versions, configv1.OperandVersion{
+			Name:    s.Name(),
+			Version: v,
+		})
+
+		if !sourceRolledOut {
+			rolledOut = false
+		}
+	}
+
+	return versions, rolledOut
+}
+
 // GetOrCreateClusterOperator gets, or if necessary, creates the
 // operator's ClusterOperator object and returns it.
 func (r *StatusReporter) GetOrCreateClusterOperator() (*configv1.ClusterOperator, error) {
@@ -80,18 +182,15 @@ func (r *StatusReporter) IsDifferentVersions(desiredVersions []osconfigv1.Operan
 // ApplyConditions applies the given conditions to the ClusterOperator
 // resource's status.
 func (r *StatusReporter) ApplyConditions(conds []configv1.ClusterOperatorStatusCondition) error {
+	versions, _ := r.operandVersions()
+
 	status := configv1.ClusterOperatorStatus{
-		Versions: []configv1.OperandVersion{
-			{
-				Name:    "cluster-autoscaler",
-				Version: version.Raw,
-			},
-		},
+		Versions:       versions,
 		RelatedObjects: r.relatedObjects,
 	}
 
 	for _, c := range conds {
-		cvorm.SetOperatorStatusCondition(&status.Conditions, c)
+		v1helpers.SetStatusCondition(&status.Conditions, c)
 	}
 
 	co, err := r.GetOrCreateClusterOperator()
@@ -125,13 +224,18 @@ func (r *StatusReporter) Available(reason, message string) error {
 			Type:   configv1.OperatorFailing,
 			Status: configv1.ConditionFalse,
 		},
+		r.degradedCondition(false, ReasonEmpty, ""),
+		r.CheckPreconditions(),
 	}
 
 	return r.ApplyConditions(conditions)
 }
 
 // Fail reports the operator as failing but available, and not
-// progressing -- optionally setting a reason and message.
+// progressing -- optionally setting a reason and message. The legacy
+// Failing condition is set immediately, while Degraded is only set to
+// true once the failure has persisted past r.degradedWindow, to avoid
+// flapping Degraded on transient errors.
 func (r *StatusReporter) Fail(reason, message string) error {
 	conditions := []configv1.ClusterOperatorStatusCondition{
 		{
@@ -148,6 +252,8 @@ func (r *StatusReporter) Fail(reason, message string) error {
 			Reason:  reason,
 			Message: message,
 		},
+		r.degradedCondition(true, reason, message),
+		r.CheckPreconditions(),
 	}
 
 	return r.ApplyConditions(conditions)
@@ -171,6 +277,8 @@ func (r *StatusReporter) Progressing(reason, message string) error {
 			Type:   configv1.OperatorFailing,
 			Status: configv1.ConditionFalse,
 		},
+		r.degradedCondition(false, ReasonEmpty, ""),
+		r.CheckPreconditions(),
 	}
 
 	return r.ApplyConditions(conditions)
@@ -180,6 +288,11 @@ func (r *StatusReporter) Progressing(reason, message string) error {
 // status.  It will poll until stopCh is closed or prerequisites are
 // satisfied, in which case it will report the operator as available
 // and return.
+//
+// Deprecated: Report polls on a fixed interval, which means status can
+// lag dependency changes by up to that interval. Use
+// NewStatusController, which recomputes status in response to informer
+// events instead of on a timer.
 func (r *StatusReporter) Report(stopCh <-chan struct{}) error {
 	interval := 15 * time.Second
 
@@ -187,9 +300,9 @@ func (r *StatusReporter) Report(stopCh <-chan struct{}) error {
 	// accordingly.  Rather than return errors and stop polling, most
 	// errors here should just be reported in the status message.
 	pollFunc := func() (bool, error) {
-		ok, err := r.CheckMachineAPI()
+		ok, message, err := r.CheckDependencies(context.TODO())
 		if err != nil {
-			r.Fail(ReasonMissingDependency, fmt.Sprintf("error checking machine-api operator status %v", err))
+			r.Fail(ReasonMissingDependency, err.Error())
 			return false, nil
 		}
 
@@ -214,7 +327,7 @@ func (r *StatusReporter) Report(stopCh <-chan struct{}) error {
 			return true, nil
 		}
 
-		r.Fail(ReasonMissingDependency, "machine-api operator not ready")
+		r.Fail(ReasonMissingDependency, message)
 		return false, nil
 	}
 
@@ -224,24 +337,23 @@ func (r *StatusReporter) Report(stopCh <-chan struct{}) error {
 // CheckMachineAPI checks the status of the machine-api-operator as
 // reported to the CVO.  It returns true if the operator is available
 // and not failing.
+// It also returns false when machine-api reports Degraded.
+//
+// Deprecated: CheckMachineAPI only ever checked a single hardcoded
+// dependency. Use AddDependency and CheckDependencies, which support an
+// arbitrary set of dependencies.
 func (r *StatusReporter) CheckMachineAPI() (bool, error) {
-	mao, err := r.client.ConfigV1().ClusterOperators().
-		Get("machine-api", metav1.GetOptions{})
-
+	ready, message, err := NewClusterOperatorDependency(r.client, "machine-api").Ready(context.TODO())
 	if err != nil {
 		glog.Errorf("failed to get dependency machine-api status: %v", err)
 		return false, err
 	}
 
-	conds := mao.Status.Conditions
-
-	if cvorm.IsOperatorStatusConditionTrue(conds, configv1.OperatorAvailable) &&
-		cvorm.IsOperatorStatusConditionFalse(conds, configv1.OperatorFailing) {
-		return true, nil
+	if !ready {
+		glog.Infof(message)
 	}
 
-	glog.Infof("machine-api-operator not ready yet")
-	return false, nil
+	return ready, nil
 }
 
 func printOperandVersions(desiredVersions []osconfigv1.OperandVersion) string {
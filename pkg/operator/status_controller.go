@@ -0,0 +1,185 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	configv1 "github.com/openshift/api/config/v1"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// statusControllerResync is the safety-net resync period for the
+// StatusController. Informer event handlers should make this
+// unnecessary in the common case, but it guards against missed events.
+const statusControllerResync = 5 * time.Minute
+
+// statusControllerKey is the single workqueue key the StatusController
+// enqueues. Status is computed from cluster-wide state rather than a
+// single object, so there is only ever one item of work.
+const statusControllerKey = "status"
+
+// StatusController watches the resources that factor into the
+// operator's ClusterOperator status and recomputes that status in
+// response to events, rather than polling on a fixed interval. It
+// replaces the polling loop driven by StatusReporter.Report.
+type StatusController struct {
+	*StatusReporter
+
+	queue workqueue.RateLimitingInterface
+
+	clusterOperatorInformer cache.SharedIndexInformer
+	deploymentInformer      appsinformers.DeploymentInformer
+
+	cacheSyncs []cache.InformerSynced
+}
+
+// NewStatusController returns a new StatusController. The informer
+// factories are expected to already be configured with any
+// namespace/label restrictions the caller wants; NewStatusController
+// only registers the event handlers it needs and does not start them.
+func NewStatusController(cfg *rest.Config, informerFactory configinformers.SharedInformerFactory, kubeInformerFactory kubeinformers.SharedInformerFactory, relatedObjects []configv1.ObjectReference) (*StatusController, error) {
+	reporter, err := NewStatusReporter(cfg, relatedObjects)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterOperators := informerFactory.Config().V1().ClusterOperators()
+	deployments := kubeInformerFactory.Apps().V1().Deployments()
+
+	c := &StatusController{
+		StatusReporter:          reporter,
+		queue:                   workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		clusterOperatorInformer: clusterOperators.Informer(),
+		deploymentInformer:      deployments,
+		cacheSyncs: []cache.InformerSynced{
+			clusterOperators.Informer().HasSynced,
+			deployments.Informer().HasSynced,
+		},
+	}
+
+	c.clusterOperatorInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue() },
+		UpdateFunc: func(old, new interface{}) { c.enqueue() },
+		DeleteFunc: func(obj interface{}) { c.enqueue() },
+	})
+
+	// The cluster-autoscaler Deployment drives both the OperandImagePrecondition
+	// check and the reported operand version/rollout state, so changes
+	// to it need to trigger a resync just like changes to a dependency
+	// ClusterOperator do.
+	deployments.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: isClusterAutoscalerDeployment,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueue() },
+			UpdateFunc: func(old, new interface{}) { c.enqueue() },
+			DeleteFunc: func(obj interface{}) { c.enqueue() },
+		},
+	})
+
+	return c, nil
+}
+
+// isClusterAutoscalerDeployment reports whether obj is the Deployment
+// this operator manages for the cluster-autoscaler operand, so the
+// controller only resyncs on events for that Deployment rather than any
+// Deployment visible to kubeInformerFactory.
+func isClusterAutoscalerDeployment(obj interface{}) bool {
+	accessor, err := metav1.Accessor(obj)
+	if err != nil {
+		return false
+	}
+
+	return accessor.GetNamespace() == OperatorNamespace && accessor.GetName() == ClusterAutoscalerName
+}
+
+// enqueue adds the single status work item to the queue. It is safe to
+// call repeatedly; duplicate enqueues of the same key coalesce.
+func (c *StatusController) enqueue() {
+	c.queue.Add(statusControllerKey)
+}
+
+// Run waits for the controller's informer caches to sync and starts
+// workers processing the status queue. It does not start the informers
+// itself; the caller's shared factories must be started. Run blocks
+// until stopCh is closed.
+func (c *StatusController) Run(stopCh <-chan struct{}, workers int) error {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	glog.Info("Starting status controller")
+	defer glog.Info("Shutting down status controller")
+
+	if !cache.WaitForCacheSync(stopCh, c.cacheSyncs...) {
+		return fmt.Errorf("timed out waiting for status controller caches to sync")
+	}
+
+	// Seed an initial sync and make sure we never go longer than
+	// statusControllerResync without recomputing status, even if we
+	// miss an informer event.
+	c.enqueue()
+	go wait.Until(c.enqueue, statusControllerResync, stopCh)
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+
+	return nil
+}
+
+func (c *StatusController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *StatusController) processNextWorkItem() bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(); err != nil {
+		runtime.HandleError(fmt.Errorf("error syncing status: %v", err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+// sync computes the operator's current status from the watched
+// dependencies and owned resources and applies it to the
+// ClusterOperator resource. As in the old polling Report loop, errors
+// encountered while checking dependencies are reported in the status
+// message rather than returned; sync returns an error only when the
+// status itself cannot be applied.
+func (c *StatusController) sync() error {
+	ok, message, err := c.CheckDependencies(context.TODO())
+	if err != nil {
+		return c.Fail(ReasonMissingDependency, err.Error())
+	}
+
+	if !ok {
+		return c.Fail(ReasonMissingDependency, message)
+	}
+
+	versions, rolledOut := c.operandVersions()
+	if !rolledOut {
+		return c.Progressing(ReasonSyncing, fmt.Sprintf("Syncing to version %v", printOperandVersions(versions)))
+	}
+
+	return c.Available(ReasonEmpty, "")
+}
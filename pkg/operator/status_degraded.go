@@ -0,0 +1,89 @@
+package operator
+
+import (
+	"sync"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// DefaultDegradedWindow is the default length of time a failure must
+// persist before it is reflected in the Degraded condition. This
+// matches the MCO's behavior of not flapping Degraded on transient
+// errors, while still reporting the legacy Failing condition
+// immediately for consumers that have not migrated.
+const DefaultDegradedWindow = 2 * time.Minute
+
+// failureTracker records how long a failure has been ongoing so that
+// StatusReporter can suppress transient flaps before reporting
+// Degraded=True. It is safe for concurrent use, since the
+// StatusController may invoke the reporter from multiple workers.
+type failureTracker struct {
+	mu    sync.Mutex
+	since *time.Time
+	now   func() time.Time
+}
+
+func newFailureTracker(now func() time.Time) *failureTracker {
+	if now == nil {
+		now = time.Now
+	}
+	return &failureTracker{now: now}
+}
+
+// Fail records an ongoing failure and reports whether it has persisted
+// for at least window, in which case the caller should report
+// Degraded=True.
+func (t *failureTracker) Fail(window time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+
+	if t.since == nil {
+		t.since = &now
+		return false
+	}
+
+	return now.Sub(*t.since) >= window
+}
+
+// Clear records a success, resetting the failure window. Per the
+// "clear failing status on success" convention, a single success is
+// enough to un-degrade, even if the prior failure had persisted past
+// the window.
+func (t *failureTracker) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.since = nil
+}
+
+// degradedCondition returns the ClusterOperatorStatusCondition for the
+// given raw failure state, applying flap-suppression via the
+// StatusReporter's failureTracker.
+func (r *StatusReporter) degradedCondition(failing bool, reason, message string) configv1.ClusterOperatorStatusCondition {
+	if !failing {
+		r.degradedTracker.Clear()
+
+		return configv1.ClusterOperatorStatusCondition{
+			Type:   configv1.OperatorDegraded,
+			Status: configv1.ConditionFalse,
+		}
+	}
+
+	if r.degradedTracker.Fail(r.degradedWindow) {
+		return configv1.ClusterOperatorStatusCondition{
+			Type:    configv1.OperatorDegraded,
+			Status:  configv1.ConditionTrue,
+			Reason:  reason,
+			Message: message,
+		}
+	}
+
+	return configv1.ClusterOperatorStatusCondition{
+		Type:   configv1.OperatorDegraded,
+		Status: configv1.ConditionFalse,
+		Reason: ReasonDegradedPending,
+	}
+}
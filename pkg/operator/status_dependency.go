@@ -0,0 +1,94 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	osconfig "github.com/openshift/client-go/config/clientset/versioned"
+	cvorm "github.com/openshift/cluster-version-operator/lib/resourcemerge"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Dependency represents a cluster component the operator relies on to
+// function correctly. The report loop checks every registered
+// Dependency on each cycle, and reports the operator as Failing when
+// any of them is not ready. Available stays True in that case; only
+// Failing (and, once the failure persists, Degraded) changes.
+type Dependency interface {
+	// Name identifies the dependency in status messages.
+	Name() string
+
+	// Ready returns whether the dependency is ready, a human readable
+	// message describing its state, and an error if the dependency
+	// could not be checked at all.
+	Ready(ctx context.Context) (bool, string, error)
+}
+
+// ClusterOperatorDependency is a Dependency satisfied by another
+// ClusterOperator reporting itself Available and neither Degraded nor
+// Failing.
+type ClusterOperatorDependency struct {
+	client osconfig.Interface
+	name   string
+}
+
+// NewClusterOperatorDependency returns a ClusterOperatorDependency that
+// checks the named peer ClusterOperator using client.
+func NewClusterOperatorDependency(client osconfig.Interface, name string) *ClusterOperatorDependency {
+	return &ClusterOperatorDependency{
+		client: client,
+		name:   name,
+	}
+}
+
+// Name implements Dependency.
+func (d *ClusterOperatorDependency) Name() string {
+	return d.name
+}
+
+// Ready implements Dependency. A peer operator is considered ready when
+// it reports Available=True and Degraded=False. Failing is also
+// checked, since peers that have not yet migrated to Degraded only set
+// the deprecated Failing condition.
+func (d *ClusterOperatorDependency) Ready(ctx context.Context) (bool, string, error) {
+	co, err := d.client.ConfigV1().ClusterOperators().Get(d.name, metav1.GetOptions{})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get dependency %s status: %v", d.name, err)
+	}
+
+	conds := co.Status.Conditions
+
+	if cvorm.IsOperatorStatusConditionTrue(conds, configv1.OperatorAvailable) &&
+		!cvorm.IsOperatorStatusConditionTrue(conds, configv1.OperatorDegraded) &&
+		!cvorm.IsOperatorStatusConditionTrue(conds, configv1.OperatorFailing) {
+		return true, "", nil
+	}
+
+	return false, fmt.Sprintf("%s operator not ready", d.name), nil
+}
+
+// AddDependency registers a Dependency that will be checked on every
+// report cycle.
+func (r *StatusReporter) AddDependency(d Dependency) {
+	r.dependencies = append(r.dependencies, d)
+}
+
+// CheckDependencies checks all registered dependencies and returns
+// whether all of them are ready, along with a message naming the first
+// dependency found to be unready (or the error encountered while
+// checking it).
+func (r *StatusReporter) CheckDependencies(ctx context.Context) (bool, string, error) {
+	for _, d := range r.dependencies {
+		ready, message, err := d.Ready(ctx)
+		if err != nil {
+			return false, "", fmt.Errorf("error checking %s dependency status: %v", d.Name(), err)
+		}
+
+		if !ready {
+			return false, message, nil
+		}
+	}
+
+	return true, "", nil
+}
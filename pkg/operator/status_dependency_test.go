@@ -0,0 +1,77 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	fakeconfig "github.com/openshift/client-go/config/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterOperatorDependencyReady(t *testing.T) {
+	cases := []struct {
+		name       string
+		conditions []configv1.ClusterOperatorStatusCondition
+		wantReady  bool
+	}{
+		{
+			name: "available and clean",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+				{Type: configv1.OperatorDegraded, Status: configv1.ConditionFalse},
+				{Type: configv1.OperatorFailing, Status: configv1.ConditionFalse},
+			},
+			wantReady: true,
+		},
+		{
+			name: "available but degraded",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+				{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue},
+			},
+			wantReady: false,
+		},
+		{
+			name: "available but failing",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+				{Type: configv1.OperatorFailing, Status: configv1.ConditionTrue},
+			},
+			wantReady: false,
+		},
+		{
+			name: "not available",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionFalse},
+			},
+			wantReady: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := fakeconfig.NewSimpleClientset(&configv1.ClusterOperator{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine-api"},
+				Status: configv1.ClusterOperatorStatus{
+					Conditions: c.conditions,
+				},
+			})
+
+			dep := NewClusterOperatorDependency(client, "machine-api")
+
+			ready, message, err := dep.Ready(context.TODO())
+			if err != nil {
+				t.Fatalf("Ready: %v", err)
+			}
+
+			if ready != c.wantReady {
+				t.Errorf("Ready() = %v (message %q), want %v", ready, message, c.wantReady)
+			}
+
+			if !ready && message == "" {
+				t.Error("expected a non-empty message when not ready")
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv1beta1 "github.com/openshift/cluster-autoscaler-operator/pkg/apis/autoscaling/v1beta1"
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Precondition represents a check that must pass in order for the
+// operator to report itself as Upgradeable.  Preconditions are run on
+// every report cycle; a failing precondition does not affect
+// Available/Progressing/Failing, only Upgradeable.
+type Precondition interface {
+	// Name identifies the precondition in status messages.
+	Name() string
+
+	// Check returns an error describing why the precondition is not
+	// met, or nil if it is safe to upgrade.
+	Check(ctx context.Context) error
+}
+
+// LegacyFieldPrecondition fails the Upgradeable condition when a
+// ClusterAutoscaler resource still sets a field that the next minor
+// version removes support for.  This gives administrators a chance to
+// migrate their configuration before the field is dropped.
+type LegacyFieldPrecondition struct {
+	client dynamic.Interface
+}
+
+// NewLegacyFieldPrecondition returns a LegacyFieldPrecondition
+// configured with a dynamic client built from cfg.
+func NewLegacyFieldPrecondition(cfg *rest.Config) *LegacyFieldPrecondition {
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return &LegacyFieldPrecondition{}
+	}
+
+	return &LegacyFieldPrecondition{client: client}
+}
+
+// Name implements Precondition.
+func (p *LegacyFieldPrecondition) Name() string {
+	return "LegacyFieldRemoval"
+}
+
+// Check implements Precondition. It lists all ClusterAutoscaler
+// resources and fails if any still populate a field slated for
+// removal.
+func (p *LegacyFieldPrecondition) Check(ctx context.Context) error {
+	if p.client == nil {
+		return nil
+	}
+
+	resource := autoscalingv1beta1.SchemeGroupVersion.WithResource("clusterautoscalers")
+
+	list, err := p.client.Resource(resource).List(metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list ClusterAutoscalers: %v", err)
+	}
+
+	for _, item := range list.Items {
+		_, found, err := unstructured.NestedString(item.Object, "spec", "scaleDown", "delayAfterAdd")
+		if err != nil {
+			return fmt.Errorf("ClusterAutoscaler %q has a malformed spec.scaleDown.delayAfterAdd: %v", item.GetName(), err)
+		}
+		if found {
+			return fmt.Errorf("ClusterAutoscaler %q sets spec.scaleDown.delayAfterAdd, which is removed in the next release", item.GetName())
+		}
+	}
+
+	return nil
+}
+
+// MachineSetExistsPrecondition fails the Upgradeable condition when a
+// MachineAutoscaler references a MachineSet that no longer exists in the
+// cluster, since the next version enforces that the reference resolve.
+type MachineSetExistsPrecondition struct {
+	client dynamic.Interface
+}
+
+// NewMachineSetExistsPrecondition returns a MachineSetExistsPrecondition
+// configured with a dynamic client built from cfg.
+func NewMachineSetExistsPrecondition(cfg *rest.Config) *MachineSetExistsPrecondition {
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return &MachineSetExistsPrecondition{}
+	}
+
+	return &MachineSetExistsPrecondition{client: client}
+}
+
+// Name implements Precondition.
+func (p *MachineSetExistsPrecondition) Name() string {
+	return "MachineAutoscalerTargetsExist"
+}
+
+// Check implements Precondition. It lists all MachineAutoscaler
+// resources and fails if any reference a MachineSet that cannot be
+// found.
+func (p *MachineSetExistsPrecondition) Check(ctx context.Context) error {
+	if p.client == nil {
+		return nil
+	}
+
+	maResource := autoscalingv1beta1.SchemeGroupVersion.WithResource("machineautoscalers")
+	msResource := machinev1beta1.SchemeGroupVersion.WithResource("machinesets")
+
+	machineAutoscalers, err := p.client.Resource(maResource).List(metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list MachineAutoscalers: %v", err)
+	}
+
+	for _, ma := range machineAutoscalers.Items {
+		// MachineAutoscalerSpec.ScaleTargetRef is a CrossVersionObjectReference:
+		// it has no Namespace field, since the MachineSet it targets always
+		// lives in the MachineAutoscaler's own namespace.
+		targetName, found, err := unstructured.NestedString(ma.Object, "spec", "scaleTargetRef", "name")
+		if err != nil {
+			return fmt.Errorf("MachineAutoscaler %q has a malformed spec.scaleTargetRef: %v", ma.GetName(), err)
+		}
+
+		if !found || targetName == "" {
+			// No target set yet; nothing to check.
+			continue
+		}
+
+		ns := ma.GetNamespace()
+
+		if _, err := p.client.Resource(msResource).Namespace(ns).Get(targetName, metav1.GetOptions{}); err != nil {
+			return fmt.Errorf("MachineAutoscaler %q targets MachineSet %s/%s, which does not exist: %v", ma.GetName(), ns, targetName, err)
+		}
+	}
+
+	return nil
+}
+
+// OperandImagePrecondition fails the Upgradeable condition when the
+// running cluster-autoscaler Deployment is not at the desired image,
+// which would indicate the operand did not finish rolling out before
+// the upgrade was requested.
+type OperandImagePrecondition struct {
+	client kubernetes.Interface
+}
+
+// NewOperandImagePrecondition returns an OperandImagePrecondition
+// configured with a clientset built from cfg.
+func NewOperandImagePrecondition(cfg *rest.Config) *OperandImagePrecondition {
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return &OperandImagePrecondition{}
+	}
+
+	return &OperandImagePrecondition{client: client}
+}
+
+// Name implements Precondition.
+func (p *OperandImagePrecondition) Name() string {
+	return "OperandAtDesiredImage"
+}
+
+// Check implements Precondition. It fetches the operator's managed
+// cluster-autoscaler Deployment and fails if its pods have not rolled
+// out to the image set on the pod template.
+func (p *OperandImagePrecondition) Check(ctx context.Context) error {
+	if p.client == nil {
+		return nil
+	}
+
+	deployment, err := p.client.AppsV1().Deployments(OperatorNamespace).
+		Get(ClusterAutoscalerName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		// No deployment yet is not a reason to block upgrades; the
+		// dependency/availability checks already cover that case.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get cluster-autoscaler deployment: %v", err)
+	}
+
+	if !deploymentRolledOut(deployment) {
+		return fmt.Errorf("cluster-autoscaler deployment %q has not finished rolling out to its desired image", deployment.Name)
+	}
+
+	return nil
+}
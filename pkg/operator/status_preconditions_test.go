@@ -0,0 +1,147 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	autoscalingv1beta1 "github.com/openshift/cluster-autoscaler-operator/pkg/apis/autoscaling/v1beta1"
+	machinev1beta1 "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func newUnstructured(gvk schema.GroupVersionKind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	if spec != nil {
+		u.Object["spec"] = spec
+	}
+	return u
+}
+
+func TestLegacyFieldPreconditionCheck(t *testing.T) {
+	caGVK := autoscalingv1beta1.SchemeGroupVersion.WithKind("ClusterAutoscaler")
+	caListKind := autoscalingv1beta1.SchemeGroupVersion.WithKind("ClusterAutoscalerList")
+
+	cases := []struct {
+		name    string
+		objects []runtime.Object
+		wantErr bool
+	}{
+		{
+			name: "no legacy field set",
+			objects: []runtime.Object{
+				newUnstructured(caGVK, "", "default", map[string]interface{}{
+					"scaleDown": map[string]interface{}{},
+				}),
+			},
+			wantErr: false,
+		},
+		{
+			name: "legacy field set",
+			objects: []runtime.Object{
+				newUnstructured(caGVK, "", "default", map[string]interface{}{
+					"scaleDown": map[string]interface{}{
+						"delayAfterAdd": "10m",
+					},
+				}),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			client := fake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+				map[schema.GroupVersionResource]string{
+					autoscalingv1beta1.SchemeGroupVersion.WithResource("clusterautoscalers"): caListKind.Kind,
+				},
+				c.objects...,
+			)
+
+			p := &LegacyFieldPrecondition{client: client}
+
+			err := p.Check(context.TODO())
+			if c.wantErr && err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMachineSetExistsPreconditionCheck(t *testing.T) {
+	maGVK := autoscalingv1beta1.SchemeGroupVersion.WithKind("MachineAutoscaler")
+	maListKind := autoscalingv1beta1.SchemeGroupVersion.WithKind("MachineAutoscalerList")
+	msGVK := machinev1beta1.SchemeGroupVersion.WithKind("MachineSet")
+	msListKind := machinev1beta1.SchemeGroupVersion.WithKind("MachineSetList")
+
+	machineSet := newUnstructured(msGVK, "openshift-machine-api", "worker-us-east-1a", nil)
+
+	cases := []struct {
+		name    string
+		objects []runtime.Object
+		wantErr bool
+	}{
+		{
+			name: "target exists",
+			objects: []runtime.Object{
+				newUnstructured(maGVK, "openshift-machine-api", "worker-us-east-1a", map[string]interface{}{
+					"scaleTargetRef": map[string]interface{}{
+						"name": "worker-us-east-1a",
+					},
+				}),
+				machineSet,
+			},
+			wantErr: false,
+		},
+		{
+			name: "target missing",
+			objects: []runtime.Object{
+				newUnstructured(maGVK, "openshift-machine-api", "worker-us-east-1b", map[string]interface{}{
+					"scaleTargetRef": map[string]interface{}{
+						"name": "worker-us-east-1b",
+					},
+				}),
+			},
+			wantErr: true,
+		},
+		{
+			name: "target unset",
+			objects: []runtime.Object{
+				newUnstructured(maGVK, "openshift-machine-api", "worker-us-east-1c", map[string]interface{}{}),
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			client := fake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+				map[schema.GroupVersionResource]string{
+					autoscalingv1beta1.SchemeGroupVersion.WithResource("machineautoscalers"): maListKind.Kind,
+					machinev1beta1.SchemeGroupVersion.WithResource("machinesets"):            msListKind.Kind,
+				},
+				c.objects...,
+			)
+
+			p := &MachineSetExistsPrecondition{client: client}
+
+			err := p.Check(context.TODO())
+			if c.wantErr && err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
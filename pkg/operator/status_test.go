@@ -0,0 +1,198 @@
+package operator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	fakeconfig "github.com/openshift/client-go/config/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestReporter returns a StatusReporter backed by a fake configv1
+// clientset and a controllable clock, bypassing NewStatusReporter so
+// tests don't need a *rest.Config.
+func newTestReporter(t *testing.T, now func() time.Time) *StatusReporter {
+	t.Helper()
+
+	client := fakeconfig.NewSimpleClientset(&configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: OperatorName},
+	})
+
+	return &StatusReporter{
+		client:          client,
+		degradedTracker: newFailureTracker(now),
+		degradedWindow:  DefaultDegradedWindow,
+	}
+}
+
+// TestDegradedConditionSuppressesTransientFailures and
+// TestDegradedConditionClearsOnSuccess exercise the flap-suppression
+// timer in isolation. TestFlappingDependencyDegradesAfterWindow below
+// drives the same logic through a flapping dependency ClusterOperator
+// end-to-end.
+func TestDegradedConditionSuppressesTransientFailures(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	r := newTestReporter(t, clock)
+
+	// A single failure, well within the window, should not degrade.
+	cond := r.degradedCondition(true, ReasonMissingDependency, "dependency not ready")
+	if cond.Status != configv1.ConditionFalse {
+		t.Fatalf("expected Degraded=False on first failure, got %s", cond.Status)
+	}
+
+	// Flap: a success in between should reset the window.
+	r.degradedCondition(false, ReasonEmpty, "")
+
+	now = now.Add(DefaultDegradedWindow + time.Second)
+	cond = r.degradedCondition(true, ReasonMissingDependency, "dependency not ready")
+	if cond.Status != configv1.ConditionFalse {
+		t.Fatalf("expected Degraded=False immediately after a success resets the window, got %s", cond.Status)
+	}
+
+	// Now let the same failure persist past the window without an
+	// intervening success.
+	now = now.Add(DefaultDegradedWindow + time.Second)
+	cond = r.degradedCondition(true, ReasonMissingDependency, "dependency not ready")
+	if cond.Status != configv1.ConditionTrue {
+		t.Fatalf("expected Degraded=True once failure persists past the window, got %s", cond.Status)
+	}
+	if cond.Reason != ReasonMissingDependency {
+		t.Errorf("expected reason %q, got %q", ReasonMissingDependency, cond.Reason)
+	}
+}
+
+func TestDegradedConditionClearsOnSuccess(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	r := newTestReporter(t, clock)
+
+	r.degradedCondition(true, ReasonMissingDependency, "dependency not ready")
+	now = now.Add(DefaultDegradedWindow + time.Second)
+	cond := r.degradedCondition(true, ReasonMissingDependency, "dependency not ready")
+	if cond.Status != configv1.ConditionTrue {
+		t.Fatalf("expected Degraded=True after the failure window elapses, got %s", cond.Status)
+	}
+
+	// A single success clears Degraded immediately, even though the
+	// failure had persisted past the window.
+	cond = r.degradedCondition(false, ReasonEmpty, "")
+	if cond.Status != configv1.ConditionFalse {
+		t.Fatalf("expected Degraded=False immediately on success, got %s", cond.Status)
+	}
+
+	now = now.Add(time.Second)
+	cond = r.degradedCondition(true, ReasonMissingDependency, "dependency not ready")
+	if cond.Status != configv1.ConditionFalse {
+		t.Fatalf("expected a fresh failure after a success to require the full window again, got %s", cond.Status)
+	}
+}
+
+// TestFlappingDependencyDegradesAfterWindow drives a flapping
+// machine-api ClusterOperator through CheckDependencies, Fail, and
+// Available against a fake configv1 clientset, and asserts on the
+// Degraded condition the reporter actually persists.
+func TestFlappingDependencyDegradesAfterWindow(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	machineAPI := &configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine-api"},
+		Status: configv1.ClusterOperatorStatus{
+			Conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionFalse},
+			},
+		},
+	}
+
+	client := fakeconfig.NewSimpleClientset(
+		&configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: OperatorName}},
+		machineAPI,
+	)
+
+	r := &StatusReporter{
+		client:          client,
+		degradedTracker: newFailureTracker(clock),
+		degradedWindow:  DefaultDegradedWindow,
+		dependencies:    []Dependency{NewClusterOperatorDependency(client, "machine-api")},
+	}
+
+	reportDependencyState := func() {
+		ready, message, err := r.CheckDependencies(context.TODO())
+		if err != nil {
+			t.Fatalf("CheckDependencies: %v", err)
+		}
+		if ready {
+			if err := r.Available(ReasonEmpty, ""); err != nil {
+				t.Fatalf("Available: %v", err)
+			}
+			return
+		}
+		if err := r.Fail(ReasonMissingDependency, message); err != nil {
+			t.Fatalf("Fail: %v", err)
+		}
+	}
+
+	degraded := func() configv1.ConditionStatus {
+		co, err := client.ConfigV1().ClusterOperators().Get(OperatorName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get ClusterOperator: %v", err)
+		}
+		for _, c := range co.Status.Conditions {
+			if c.Type == configv1.OperatorDegraded {
+				return c.Status
+			}
+		}
+		t.Fatal("Degraded condition not found")
+		return ""
+	}
+
+	// machine-api starts out not ready; the first report should not
+	// yet flip Degraded, since the failure hasn't persisted.
+	reportDependencyState()
+	if got := degraded(); got != configv1.ConditionFalse {
+		t.Fatalf("expected Degraded=False on first failure, got %s", got)
+	}
+
+	// machine-api flaps briefly to ready and back to not ready; this
+	// should reset the failure window rather than accumulate it.
+	machineAPI.Status.Conditions[0].Status = configv1.ConditionTrue
+	if _, err := client.ConfigV1().ClusterOperators().UpdateStatus(machineAPI); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	reportDependencyState()
+	if got := degraded(); got != configv1.ConditionFalse {
+		t.Fatalf("expected Degraded=False while machine-api is briefly ready, got %s", got)
+	}
+
+	machineAPI.Status.Conditions[0].Status = configv1.ConditionFalse
+	if _, err := client.ConfigV1().ClusterOperators().UpdateStatus(machineAPI); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	now = now.Add(time.Second)
+	reportDependencyState()
+	if got := degraded(); got != configv1.ConditionFalse {
+		t.Fatalf("expected Degraded=False immediately after the flap reset the window, got %s", got)
+	}
+
+	// machine-api now stays down past the degraded window.
+	now = now.Add(DefaultDegradedWindow + time.Second)
+	reportDependencyState()
+	if got := degraded(); got != configv1.ConditionTrue {
+		t.Fatalf("expected Degraded=True once machine-api has been down past the window, got %s", got)
+	}
+
+	// A single success clears Degraded again.
+	machineAPI.Status.Conditions[0].Status = configv1.ConditionTrue
+	if _, err := client.ConfigV1().ClusterOperators().UpdateStatus(machineAPI); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	reportDependencyState()
+	if got := degraded(); got != configv1.ConditionFalse {
+		t.Fatalf("expected Degraded=False once machine-api recovers, got %s", got)
+	}
+}
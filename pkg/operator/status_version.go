@@ -0,0 +1,119 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// OperandVersionLabel is set on a managed Deployment's pod template to
+// record the version of the operand image it runs. It takes precedence
+// over the image tag, for deployments pinned to a digest rather than a
+// tag.
+const OperandVersionLabel = "cluster-autoscaler-operator.openshift.io/version"
+
+// OperandVersionSource resolves the effective version of a managed
+// operand, as distinct from the operator's own binary version, along
+// with whether the operand has finished rolling out to it.
+type OperandVersionSource interface {
+	// Name is the OperandVersion entry name this source resolves, e.g.
+	// "cluster-autoscaler".
+	Name() string
+
+	// Version returns the operand's effective version and whether the
+	// Deployment serving it has rolled out every replica to that
+	// version.
+	Version(ctx context.Context) (version string, rolledOut bool, err error)
+}
+
+// DeploymentOperandVersionSource resolves an operand's version from a
+// Deployment's pod template: the OperandVersionLabel if set, falling
+// back to the tag (or digest) of its first container's image.
+type DeploymentOperandVersionSource struct {
+	client     kubernetes.Interface
+	name       string
+	namespace  string
+	deployment string
+}
+
+// NewDeploymentOperandVersionSource returns a DeploymentOperandVersionSource
+// for the named Deployment, using a clientset built from cfg.
+func NewDeploymentOperandVersionSource(cfg *rest.Config, name, namespace, deployment string) *DeploymentOperandVersionSource {
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return &DeploymentOperandVersionSource{name: name, namespace: namespace, deployment: deployment}
+	}
+
+	return &DeploymentOperandVersionSource{
+		client:     client,
+		name:       name,
+		namespace:  namespace,
+		deployment: deployment,
+	}
+}
+
+// Name implements OperandVersionSource.
+func (s *DeploymentOperandVersionSource) Name() string {
+	return s.name
+}
+
+// Version implements OperandVersionSource.
+func (s *DeploymentOperandVersionSource) Version(ctx context.Context) (string, bool, error) {
+	if s.client == nil {
+		return "", false, fmt.Errorf("no client configured for %s operand version source", s.name)
+	}
+
+	deployment, err := s.client.AppsV1().Deployments(s.namespace).Get(s.deployment, metav1.GetOptions{})
+	if err != nil {
+		return "", false, err
+	}
+
+	version := deployment.Spec.Template.Labels[OperandVersionLabel]
+	if version == "" {
+		version = imageVersion(containerImage(deployment))
+	}
+
+	return version, deploymentRolledOut(deployment), nil
+}
+
+// containerImage returns the image of a Deployment's first container,
+// which by convention is the operand container in the Deployments this
+// operator manages.
+func containerImage(d *appsv1.Deployment) string {
+	if len(d.Spec.Template.Spec.Containers) == 0 {
+		return ""
+	}
+
+	return d.Spec.Template.Spec.Containers[0].Image
+}
+
+// imageVersion extracts the tag or digest from a container image
+// reference, since that is the closest approximation of a version we
+// can read off the Deployment spec directly.
+func imageVersion(image string) string {
+	if i := strings.LastIndex(image, "@"); i != -1 {
+		return image[i+1:]
+	}
+
+	// Only treat a colon after the last path separator as a tag
+	// separator, so a registry host:port (e.g. "host:5000/repo") isn't
+	// mistaken for one.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+
+	return image
+}
+
+// deploymentRolledOut reports whether d's controller has observed the
+// latest spec generation and rolled every replica out to it.
+func deploymentRolledOut(d *appsv1.Deployment) bool {
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == d.Status.Replicas &&
+		d.Status.AvailableReplicas == d.Status.Replicas
+}
@@ -0,0 +1,50 @@
+package operator
+
+import "testing"
+
+func TestImageVersion(t *testing.T) {
+	cases := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{
+			name:  "tagged image",
+			image: "repo:v1",
+			want:  "v1",
+		},
+		{
+			name:  "digest-pinned image",
+			image: "repo@sha256:abcdef",
+			want:  "sha256:abcdef",
+		},
+		{
+			name:  "image with no tag or digest",
+			image: "repo",
+			want:  "repo",
+		},
+		{
+			name:  "registry host:port with no tag",
+			image: "reg:5000/repo",
+			want:  "reg:5000/repo",
+		},
+		{
+			name:  "registry host:port with a tag",
+			image: "reg:5000/repo:v1",
+			want:  "v1",
+		},
+		{
+			name:  "registry host:port with a digest",
+			image: "reg:5000/repo@sha256:abcdef",
+			want:  "sha256:abcdef",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := imageVersion(c.image); got != c.want {
+				t.Errorf("imageVersion(%q) = %q, want %q", c.image, got, c.want)
+			}
+		})
+	}
+}